@@ -2,6 +2,7 @@ package requests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/goantor/x"
@@ -14,8 +15,13 @@ import (
 )
 
 const (
-	GetMethod  MethodType = "GET"
-	PostMethod MethodType = "POST"
+	GetMethod     MethodType = "GET"
+	PostMethod    MethodType = "POST"
+	PutMethod     MethodType = "PUT"
+	PatchMethod   MethodType = "PATCH"
+	DeleteMethod  MethodType = "DELETE"
+	HeadMethod    MethodType = "HEAD"
+	OptionsMethod MethodType = "OPTIONS"
 
 	FormType ContentType = "application/x-www-form-urlencoded"
 	JsonType ContentType = "application/json"
@@ -93,6 +99,8 @@ type Request struct {
 	Params      x.H
 	Header      http.Header
 	Timeout     time.Duration
+	Retry       *RetryPolicy
+	Middlewares []Middleware
 }
 
 func NewRequest(method MethodType, contentType ContentType, url string, params x.H, header http.Header, timeout time.Duration) *Request {
@@ -105,24 +113,47 @@ func NewRequest(method MethodType, contentType ContentType, url string, params x
 }
 
 func DoRequest(req *Request) (*http.Response, error) {
-	return do(req.Method, req.ContentType, req.Url, req.Params, req.Header, req.Timeout)
+	return DoRequestCtx(context.Background(), req)
+}
+
+func DoRequestCtx(ctx context.Context, req *Request) (*http.Response, error) {
+	if req.Retry != nil {
+		return doWithRetry(ctx, req.Method, req.ContentType, req.Url, req.Params, req.Header, req.Timeout, req.Retry, req.Middlewares)
+	}
+
+	if len(req.Middlewares) > 0 {
+		return doWithMiddlewares(ctx, req.Method, req.ContentType, req.Url, req.Params, req.Header, req.Middlewares)
+	}
+
+	return do(ctx, req.Method, req.ContentType, req.Url, req.Params, req.Header, req.Timeout)
 }
 
 func Auto(method MethodType, contentType ContentType, url string, params x.H, header http.Header, duration time.Duration) (*http.Response, error) {
+	return AutoCtx(context.Background(), method, contentType, url, params, header, duration)
+}
+
+func AutoCtx(ctx context.Context, method MethodType, contentType ContentType, url string, params x.H, header http.Header, duration time.Duration) (*http.Response, error) {
 	if method == GetMethod {
-		return Get(url, params)
+		return GetCtx(ctx, url, params)
 	}
 
 	if contentType == FormType {
-		return Form(url, params, header, duration)
+		return FormCtx(ctx, url, params, header, duration)
+	}
+
+	if contentType == MultipartType {
+		return MultipartCtx(ctx, url, params, nil, header, duration)
 	}
 
-	return Json(url, params, header, duration)
+	return JsonCtx(ctx, url, params, header, duration)
 }
 
 func Get(url string, params x.H) (*http.Response, error) {
-	//client := http.Client{}
-	return clientPool.Get(getRequestURL(url, params))
+	return GetCtx(context.Background(), url, params)
+}
+
+func GetCtx(ctx context.Context, url string, params x.H) (*http.Response, error) {
+	return do(ctx, GetMethod, "", getRequestURL(url, params), nil, http.Header{}, 0)
 }
 
 // getRequestURL 获取Get 请求
@@ -132,44 +163,80 @@ func getRequestURL(url string, params x.H) string {
 }
 
 func Form(url string, params x.H, header http.Header, duration time.Duration) (*http.Response, error) {
+	return FormCtx(context.Background(), url, params, header, duration)
+}
+
+func FormCtx(ctx context.Context, url string, params x.H, header http.Header, duration time.Duration) (*http.Response, error) {
 	if header == nil {
 		header = http.Header{}
 	}
 	header.Set("Content-Type", string(FormType))
-	return do(PostMethod, FormType, url, params, header, duration)
+	return do(ctx, PostMethod, FormType, url, params, header, duration)
 }
 
 func Json(url string, params x.H, header http.Header, duration time.Duration) (*http.Response, error) {
+	return JsonCtx(context.Background(), url, params, header, duration)
+}
+
+func JsonCtx(ctx context.Context, url string, params x.H, header http.Header, duration time.Duration) (*http.Response, error) {
 	if header == nil {
 		header = http.Header{}
 	}
 
 	header.Set("Content-Type", "application/json;charset=utf-8")
-	return do(PostMethod, JsonType, url, params, header, duration)
+	return do(ctx, PostMethod, JsonType, url, params, header, duration)
 }
 
-func do(method MethodType, contentType ContentType, url string, params x.H, header http.Header, duration time.Duration) (resp *http.Response, err error) {
-	//ctx, cancel := context.WithTimeout(context.Background(), duration)
-	//defer cancel()
+// do 发起请求; 若 duration > 0, 会从 ctx 派生出带超时的子 ctx, 并在响应体被完整读取/关闭后才释放它
+func do(ctx context.Context, method MethodType, contentType ContentType, url string, params x.H, header http.Header, duration time.Duration) (resp *http.Response, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var cancel context.CancelFunc
+	if duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, duration)
+	}
 
-	req, err := makeRequest(method, contentType, url, params)
+	req, err := makeRequest(ctx, method, contentType, url, params)
 	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
 		return
 	}
 
 	req.Header = header
-	//client := http.Client{
-	//	Transport: &http.Transport{
-	//		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-	//	},
-	//	Timeout: duration,
-	//}
 
-	return clientPool.Do(req)
+	resp, err = clientPool.Do(req)
+	if err != nil {
+		if cancel != nil {
+			cancel()
+		}
+		return
+	}
+
+	if cancel != nil {
+		resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	}
+
+	return
 }
 
-func makeRequest(method MethodType, typ ContentType, url string, params x.H) (*http.Request, error) {
-	return http.NewRequest(string(method), url, getData(typ, params))
+// cancelOnCloseBody 让超时 ctx 的 cancel 延后到响应体被读完并关闭之后再执行, 避免提前取消导致读取失败
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func makeRequest(ctx context.Context, method MethodType, typ ContentType, url string, params x.H) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, string(method), url, getData(typ, params))
 }
 
 func getData(typ ContentType, params x.H) io.Reader {
@@ -211,8 +278,12 @@ func queryParams(params x.H, format string) string {
 }
 
 func PostForm(url string, data x.H, header http.Header, requestTimeout time.Duration) (ret *Response, err error) {
+	return PostFormCtx(context.Background(), url, data, header, requestTimeout)
+}
+
+func PostFormCtx(ctx context.Context, url string, data x.H, header http.Header, requestTimeout time.Duration) (ret *Response, err error) {
 	var resp *http.Response
-	if resp, err = Form(url, data, header, requestTimeout); err != nil {
+	if resp, err = FormCtx(ctx, url, data, header, requestTimeout); err != nil {
 		return
 	}
 
@@ -221,8 +292,12 @@ func PostForm(url string, data x.H, header http.Header, requestTimeout time.Dura
 }
 
 func PostJson(url string, params x.H, header http.Header, duration time.Duration) (ret *Response, err error) {
+	return PostJsonCtx(context.Background(), url, params, header, duration)
+}
+
+func PostJsonCtx(ctx context.Context, url string, params x.H, header http.Header, duration time.Duration) (ret *Response, err error) {
 	var resp *http.Response
-	if resp, err = Json(url, params, header, duration); err != nil {
+	if resp, err = JsonCtx(ctx, url, params, header, duration); err != nil {
 		return
 	}
 
@@ -231,8 +306,12 @@ func PostJson(url string, params x.H, header http.Header, duration time.Duration
 }
 
 func FastGet(url string, data x.H) (ret *Response, err error) {
+	return FastGetCtx(context.Background(), url, data)
+}
+
+func FastGetCtx(ctx context.Context, url string, data x.H) (ret *Response, err error) {
 	var resp *http.Response
-	if resp, err = Get(url, data); err != nil {
+	if resp, err = GetCtx(ctx, url, data); err != nil {
 		return
 	}
 
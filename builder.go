@@ -0,0 +1,233 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/goantor/x"
+	"gopkg.in/yaml.v3"
+)
+
+// Builder 提供链式调用风格的请求构造方式, 作为现有函数式 helper 之上的补充
+type Builder struct {
+	method      MethodType
+	contentType ContentType
+	url         string
+	header      http.Header
+	query       x.H
+	body        io.Reader
+	timeout     time.Duration
+	ctx         context.Context
+
+	multipartFields x.H
+	multipartFiles  []FilePart
+}
+
+// New 创建一个空白的 Builder, 默认方法为 GET
+func New() *Builder {
+	return &Builder{
+		method: GetMethod,
+		header: http.Header{},
+		ctx:    context.Background(),
+	}
+}
+
+func (b *Builder) SetMethod(method MethodType) *Builder {
+	b.method = method
+	return b
+}
+
+func (b *Builder) SetURL(url string) *Builder {
+	b.url = url
+	return b
+}
+
+func (b *Builder) SetHeader(key, value string) *Builder {
+	b.header.Set(key, value)
+	return b
+}
+
+func (b *Builder) SetQuery(params x.H) *Builder {
+	b.query = params
+	return b
+}
+
+// SetJSON 将 body 序列化为 JSON 并设置 Content-Type
+func (b *Builder) SetJSON(body interface{}) *Builder {
+	bs, _ := json.Marshal(body)
+	b.contentType = JsonType
+	b.header.Set("Content-Type", "application/json;charset=utf-8")
+	b.body = bytes.NewReader(bs)
+	return b
+}
+
+// SetForm 将 data 编码为 x-www-form-urlencoded 并设置 Content-Type
+func (b *Builder) SetForm(data x.H) *Builder {
+	b.contentType = FormType
+	b.header.Set("Content-Type", string(FormType))
+	b.body = bytes.NewReader([]byte(queryParams(data, "")))
+	return b
+}
+
+// SetMultipart 将请求体切换为 multipart/form-data, 文件字段以流式方式发送
+func (b *Builder) SetMultipart(fields x.H, files []FilePart) *Builder {
+	b.contentType = MultipartType
+	b.method = PostMethod
+	b.multipartFields = fields
+	b.multipartFiles = files
+	return b
+}
+
+func (b *Builder) SetBearer(token string) *Builder {
+	b.header.Set("Authorization", "Bearer "+token)
+	return b
+}
+
+func (b *Builder) SetBasicAuth(user, pass string) *Builder {
+	b.header.Set("Authorization", "Basic "+basicAuthValue(user, pass))
+	return b
+}
+
+func (b *Builder) SetTimeout(d time.Duration) *Builder {
+	b.timeout = d
+	return b
+}
+
+func (b *Builder) SetContext(ctx context.Context) *Builder {
+	b.ctx = ctx
+	return b
+}
+
+// Do 发出请求并返回可绑定到具体类型的 BuilderResponse
+func (b *Builder) Do() (*BuilderResponse, error) {
+	url := b.url
+	if b.method == GetMethod && b.query != nil {
+		url = getRequestURL(url, b.query)
+	} else if b.query != nil {
+		url = fmt.Sprintf("%s?%s", url, queryParams(b.query, ""))
+	}
+
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var resp *http.Response
+	var err error
+	if b.contentType == MultipartType {
+		resp, err = MultipartCtx(ctx, url, b.multipartFields, b.multipartFiles, b.header, b.timeout)
+	} else {
+		resp, err = b.doPlain(ctx, url)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &BuilderResponse{raw: resp}, nil
+}
+
+func (b *Builder) doPlain(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, string(b.method), url, b.body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = b.header
+
+	client := clientPool
+	if b.timeout > 0 {
+		clone := *clientPool
+		clone.Timeout = b.timeout
+		client = &clone
+	}
+
+	return client.Do(req)
+}
+
+func basicAuthValue(user, pass string) string {
+	req := &http.Request{Header: http.Header{}}
+	req.SetBasicAuth(user, pass)
+	return req.Header.Get("Authorization")[len("Basic "):]
+}
+
+// BuilderResponse 封装 Builder.Do 的结果, 支持绑定到多种目标格式; 响应体在首次
+// 被需要完整字节的 Bind* 方法访问时才读取并缓存; BindWriter 在尚无缓存时直接流式转发且不写入缓存,
+// 已有缓存时则写出缓存内容
+type BuilderResponse struct {
+	raw      *http.Response
+	body     []byte
+	bodyRead bool
+}
+
+func (r *BuilderResponse) StatusCode() int {
+	return r.raw.StatusCode
+}
+
+// ensureBody 读取并关闭响应体, 缓存供后续 Bind* 调用复用
+func (r *BuilderResponse) ensureBody() error {
+	if r.bodyRead {
+		return nil
+	}
+
+	defer r.raw.Body.Close()
+
+	bs, err := io.ReadAll(r.raw.Body)
+	if err != nil {
+		return err
+	}
+
+	r.body = bs
+	r.bodyRead = true
+	return nil
+}
+
+func (r *BuilderResponse) BindJSON(v interface{}) error {
+	if err := r.ensureBody(); err != nil {
+		return err
+	}
+	return json.Unmarshal(r.body, v)
+}
+
+func (r *BuilderResponse) BindXML(v interface{}) error {
+	if err := r.ensureBody(); err != nil {
+		return err
+	}
+	return xml.Unmarshal(r.body, v)
+}
+
+func (r *BuilderResponse) BindYAML(v interface{}) error {
+	if err := r.ensureBody(); err != nil {
+		return err
+	}
+	return yaml.Unmarshal(r.body, v)
+}
+
+func (r *BuilderResponse) BindString() (string, error) {
+	if err := r.ensureBody(); err != nil {
+		return "", err
+	}
+	return string(r.body), nil
+}
+
+func (r *BuilderResponse) BindBytes() ([]byte, error) {
+	if err := r.ensureBody(); err != nil {
+		return nil, err
+	}
+	return r.body, nil
+}
+
+// BindWriter 将响应体直接流式拷贝到 w, 不在内存中缓冲整个响应体, 适用于下载大文件等场景
+func (r *BuilderResponse) BindWriter(w io.Writer) (int64, error) {
+	if r.bodyRead {
+		n, err := w.Write(r.body)
+		return int64(n), err
+	}
+
+	defer r.raw.Body.Close()
+	return io.Copy(w, r.raw.Body)
+}
@@ -0,0 +1,207 @@
+package requests
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// Config 描述 transport 的 TLS、代理与连接池参数, 零值等价于当前包内置的默认设置
+type Config struct {
+	InsecureSkipVerify bool
+	RootCAFile         string // PEM 格式的 CA 证书文件路径
+	RootCAs            []byte // PEM 格式的 CA 证书内容, 与 RootCAFile 二选一
+
+	ClientCertFile string // mTLS 客户端证书文件路径(PEM), 需配合 ClientKeyFile
+	ClientKeyFile  string
+	PKCS12File     string // mTLS 客户端证书, PKCS#12 bundle 路径, 与 ClientCertFile/ClientKeyFile 二选一
+	PKCS12Password string
+
+	MinTLSVersion uint16
+	MaxTLSVersion uint16
+
+	ProxyURL  string // 为空则不走代理; 与 ProxyFunc 二选一
+	ProxyFunc func(*http.Request) (*url.URL, error)
+
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	ExpectContinueTimeout time.Duration
+	ClientTimeout         time.Duration
+}
+
+// Client 是一套独立的 transport/clientPool, 供需要与包级默认配置隔离的调用方使用
+type Client struct {
+	Transport *http.Transport
+	Pool      *http.Client
+}
+
+// NewClient 依据 cfg 构建一套独立的连接池, 不影响包级默认的 clientPool
+func NewClient(cfg Config) (*Client, error) {
+	tr, err := buildHTTPTransport(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &http.Client{
+		Transport: tr,
+		Timeout:   orDefault(cfg.ClientTimeout, 30*time.Second),
+	}
+
+	return &Client{Transport: tr, Pool: pool}, nil
+}
+
+// Configure 用 cfg 重建包级默认的 transport/clientPool, 现有调用方无需改动即可生效
+func Configure(cfg Config) error {
+	client, err := NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	transport = client.Transport
+	clientPool.Transport = buildTransport(transport, middlewares)
+	clientPool.Timeout = client.Pool.Timeout
+
+	return nil
+}
+
+func buildHTTPTransport(cfg Config) (*http.Transport, error) {
+	tlsConfig, err := buildTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := &http.Transport{
+		MaxIdleConns:           orDefaultInt(cfg.MaxIdleConns, 5000),
+		MaxIdleConnsPerHost:    orDefaultInt(cfg.MaxIdleConnsPerHost, 1000),
+		MaxConnsPerHost:        orDefaultInt(cfg.MaxConnsPerHost, 200),
+		IdleConnTimeout:        orDefault(cfg.IdleConnTimeout, 90*time.Second),
+		TLSHandshakeTimeout:    orDefault(cfg.TLSHandshakeTimeout, 10*time.Second),
+		ResponseHeaderTimeout:  orDefault(cfg.ResponseHeaderTimeout, 5*time.Second),
+		ExpectContinueTimeout:  orDefault(cfg.ExpectContinueTimeout, 1*time.Second),
+		MaxResponseHeaderBytes: 10 << 20,
+		DialContext: (&net.Dialer{
+			Timeout:   orDefault(cfg.DialTimeout, 30*time.Second),
+			KeepAlive: orDefault(cfg.KeepAlive, 30*time.Second),
+		}).DialContext,
+		TLSClientConfig: tlsConfig,
+	}
+
+	if cfg.ProxyFunc != nil {
+		tr.Proxy = cfg.ProxyFunc
+	} else if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("requests: parse proxy url: %w", err)
+		}
+		tr.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	return tr, nil
+}
+
+func buildTLSConfig(cfg Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+		MinVersion:         cfg.MinTLSVersion,
+		MaxVersion:         cfg.MaxTLSVersion,
+	}
+
+	rootCAs, err := loadRootCAs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if rootCAs != nil {
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	cert, err := loadClientCert(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func loadRootCAs(cfg Config) (*x509.CertPool, error) {
+	pem := cfg.RootCAs
+	if cfg.RootCAFile != "" {
+		bs, err := os.ReadFile(cfg.RootCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("requests: read root ca file: %w", err)
+		}
+		pem = bs
+	}
+
+	if len(pem) == 0 {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("requests: no certificates found in root ca PEM")
+	}
+
+	return pool, nil
+}
+
+// loadClientCert 优先使用 PKCS#12 bundle, 否则回退到 PEM 证书+私钥对
+func loadClientCert(cfg Config) (*tls.Certificate, error) {
+	if cfg.PKCS12File != "" {
+		bs, err := os.ReadFile(cfg.PKCS12File)
+		if err != nil {
+			return nil, fmt.Errorf("requests: read pkcs12 file: %w", err)
+		}
+
+		key, certificate, err := pkcs12.Decode(bs, cfg.PKCS12Password)
+		if err != nil {
+			return nil, fmt.Errorf("requests: decode pkcs12 bundle: %w", err)
+		}
+
+		cert := tls.Certificate{
+			Certificate: [][]byte{certificate.Raw},
+			PrivateKey:  key,
+			Leaf:        certificate,
+		}
+		return &cert, nil
+	}
+
+	if cfg.ClientCertFile != "" && cfg.ClientKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("requests: load client cert/key: %w", err)
+		}
+		return &cert, nil
+	}
+
+	return nil, nil
+}
+
+func orDefault(d, fallback time.Duration) time.Duration {
+	if d > 0 {
+		return d
+	}
+	return fallback
+}
+
+func orDefaultInt(v, fallback int) int {
+	if v > 0 {
+		return v
+	}
+	return fallback
+}
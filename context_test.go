@@ -0,0 +1,53 @@
+package requests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetCtx_CancellationPropagates(t *testing.T) {
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	defer server.Close()
+	defer close(block)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err := GetCtx(ctx, server.URL, nil)
+	if err == nil {
+		t.Fatal("expected an error once the context was cancelled")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestDoRequest_PerCallTimeoutOverridesPoolDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	req := &Request{Method: GetMethod, Url: server.URL, Timeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	_, err := DoRequest(req)
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("expected the per-call timeout to trigger an error")
+	}
+	if elapsed > time.Second {
+		t.Fatalf("request took %s, per-call timeout did not override the 30s pool default", elapsed)
+	}
+}
@@ -0,0 +1,206 @@
+package requests
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/goantor/x"
+)
+
+// Middleware 包装一个 http.RoundTripper, 用于在请求/响应链路上插入横切逻辑
+type Middleware func(http.RoundTripper) http.RoundTripper
+
+var middlewares []Middleware
+
+// Use 注册全局中间件, 它们会按注册顺序依次包裹 transport
+func Use(mw ...Middleware) {
+	middlewares = append(middlewares, mw...)
+	clientPool.Transport = buildTransport(transport, middlewares)
+}
+
+func buildTransport(base http.RoundTripper, mw []Middleware) http.RoundTripper {
+	rt := base
+	for i := len(mw) - 1; i >= 0; i-- {
+		rt = mw[i](rt)
+	}
+
+	return rt
+}
+
+// applyMiddlewares 用于给单次请求叠加只在本次调用生效的中间件
+func applyMiddlewares(base http.RoundTripper, mw []Middleware) http.RoundTripper {
+	if len(mw) == 0 {
+		return base
+	}
+
+	return buildTransport(base, mw)
+}
+
+type requestIdKey struct{}
+
+// WithRequestId 将 request id 放入 context, 供 RequestIdMiddleware 读取
+func WithRequestId(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIdKey{}, id)
+}
+
+var requestIdSeq uint64
+
+// RequestIdMiddleware 为请求注入 X-Request-Id 请求头, 优先使用 context 中已有的值
+func RequestIdMiddleware(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		id, _ := req.Context().Value(requestIdKey{}).(string)
+		if id == "" {
+			id = fmt.Sprintf("%d-%d", time.Now().UnixNano(), atomic.AddUint64(&requestIdSeq, 1))
+		}
+
+		req.Header.Set("X-Request-Id", id)
+		return next.RoundTrip(req)
+	})
+}
+
+// Logger 是日志中间件使用的最小日志接口, 调用方可以接入自己的日志库
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+// LoggingMiddleware 记录方法、URL、状态码、耗时与收发字节数
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			var reqBytes int64
+			if req.ContentLength > 0 {
+				reqBytes = req.ContentLength
+			}
+
+			resp, err := next.RoundTrip(req)
+			duration := time.Since(start)
+
+			if err != nil {
+				logger.Printf("method=%s url=%s error=%v duration=%s bytes_in=%d", req.Method, req.URL, err, duration, reqBytes)
+				return resp, err
+			}
+
+			logger.Printf("method=%s url=%s status=%d duration=%s bytes_in=%d bytes_out=%d", req.Method, req.URL, resp.StatusCode, duration, reqBytes, resp.ContentLength)
+			return resp, err
+		})
+	}
+}
+
+// DecompressMiddleware 透明解码 gzip/deflate 编码的响应体
+func DecompressMiddleware(next http.RoundTripper) http.RoundTripper {
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Header.Get("Accept-Encoding") == "" {
+			req.Header.Set("Accept-Encoding", "gzip, deflate")
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil || resp == nil {
+			return resp, err
+		}
+
+		switch resp.Header.Get("Content-Encoding") {
+		case "gzip":
+			gz, gzErr := gzip.NewReader(resp.Body)
+			if gzErr != nil {
+				resp.Body.Close()
+				return nil, gzErr
+			}
+			resp.Body = &decompressBody{Reader: gz, underlying: resp.Body}
+			resp.Header.Del("Content-Encoding")
+		case "deflate":
+			resp.Body = &decompressBody{Reader: flate.NewReader(resp.Body), underlying: resp.Body}
+			resp.Header.Del("Content-Encoding")
+		}
+
+		return resp, nil
+	})
+}
+
+// decompressBody 让 gzip/flate 的 Close 与原始响应体的 Close 一起执行, 避免连接泄漏——
+// gzip.Reader/flate 的 Close 按文档约定不会关闭底层 reader
+type decompressBody struct {
+	io.Reader
+	underlying io.ReadCloser
+}
+
+func (b *decompressBody) Close() error {
+	closeErr := b.underlying.Close()
+	if closer, ok := b.Reader.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			return err
+		}
+	}
+	return closeErr
+}
+
+// UserAgentMiddleware 在多个 user-agent 间轮询, agents 为空时不做任何修改
+func UserAgentMiddleware(agents ...string) Middleware {
+	var counter uint64
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if len(agents) > 0 {
+				i := atomic.AddUint64(&counter, 1)
+				req.Header.Set("User-Agent", agents[(i-1)%uint64(len(agents))])
+			}
+
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// MetricsRecorder 接收每次请求完成后的观测数据, 供 Prometheus 等监控系统采集
+type MetricsRecorder interface {
+	Observe(method string, status int, duration time.Duration)
+}
+
+// MetricsMiddleware 在每次请求完成后上报指标
+func MetricsMiddleware(recorder MetricsRecorder) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			recorder.Observe(req.Method, status, time.Since(start))
+
+			return resp, err
+		})
+	}
+}
+
+// doWithMiddlewares 在共享 transport 的基础上叠加本次调用专属的中间件, 不影响全局 clientPool
+func doWithMiddlewares(ctx context.Context, method MethodType, contentType ContentType, url string, params x.H, header http.Header, mw []Middleware) (*http.Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	req, err := makeRequest(ctx, method, contentType, url, params)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header = header
+
+	client := &http.Client{
+		Transport: applyMiddlewares(clientPool.Transport, mw),
+		Timeout:   clientPool.Timeout,
+	}
+
+	return client.Do(req)
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,89 @@
+package requests
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/goantor/x"
+)
+
+const MultipartType ContentType = "multipart/form-data"
+
+// FilePart 描述一个 multipart 中的文件字段, Reader 会被流式读取, 不会整体缓冲进内存
+type FilePart struct {
+	FieldName   string
+	Filename    string
+	ContentType string
+	Reader      io.Reader
+}
+
+// Multipart 以 multipart/form-data 提交 fields 与 files, 通过 io.Pipe 边写边发, 适合大文件上传
+func Multipart(url string, fields x.H, files []FilePart, header http.Header, timeout time.Duration) (*http.Response, error) {
+	return MultipartCtx(context.Background(), url, fields, files, header, timeout)
+}
+
+func MultipartCtx(ctx context.Context, url string, fields x.H, files []FilePart, header http.Header, timeout time.Duration) (*http.Response, error) {
+	pr, pw := io.Pipe()
+	writer := multipart.NewWriter(pw)
+
+	go func() {
+		pw.CloseWithError(writeMultipartBody(writer, fields, files))
+	}()
+
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", writer.FormDataContentType())
+
+	req, err := http.NewRequestWithContext(ctx, string(PostMethod), url, pr)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+
+	client := clientPool
+	if timeout > 0 {
+		clone := *clientPool
+		clone.Timeout = timeout
+		client = &clone
+	}
+
+	return client.Do(req)
+}
+
+// writeMultipartBody 依次写入普通字段与文件字段, 供 goroutine 通过 io.Pipe 边写边发调用
+func writeMultipartBody(writer *multipart.Writer, fields x.H, files []FilePart) error {
+	for k, v := range fields {
+		if err := writer.WriteField(k, fmt.Sprintf("%v", v)); err != nil {
+			return err
+		}
+	}
+
+	for _, f := range files {
+		part, err := createFilePart(writer, f)
+		if err != nil {
+			return err
+		}
+
+		if _, err = io.Copy(part, f.Reader); err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
+
+func createFilePart(writer *multipart.Writer, f FilePart) (io.Writer, error) {
+	if f.ContentType == "" {
+		return writer.CreateFormFile(f.FieldName, f.Filename)
+	}
+
+	header := make(map[string][]string)
+	header["Content-Disposition"] = []string{fmt.Sprintf(`form-data; name="%s"; filename="%s"`, f.FieldName, f.Filename)}
+	header["Content-Type"] = []string{f.ContentType}
+	return writer.CreatePart(header)
+}
@@ -0,0 +1,111 @@
+package requests
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goantor/x"
+)
+
+func TestMultipart_EchoesFieldsAndFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(10 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var size int64
+		var fileCount int
+		for _, files := range r.MultipartForm.File {
+			for _, fh := range files {
+				fileCount++
+				size += fh.Size
+			}
+		}
+
+		fmt.Fprintf(w, `{"fields":%d,"files":%d,"size":%d}`, len(r.MultipartForm.Value), fileCount, size)
+	}))
+	defer server.Close()
+
+	fields := x.H{"name": "alice"}
+	files := []FilePart{{FieldName: "file", Filename: "a.txt", Reader: strings.NewReader("hello world")}}
+
+	resp, err := Multipart(server.URL, fields, files, nil, 5*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading response: %v", err)
+	}
+
+	body := string(bs)
+	if !strings.Contains(body, `"fields":1`) || !strings.Contains(body, `"files":1`) || !strings.Contains(body, `"size":11`) {
+		t.Fatalf("unexpected echo response: %s", body)
+	}
+}
+
+// zeroReader 产生无限个零字节, 用于在不分配真实大缓冲区的情况下模拟大文件上传
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+func TestMultipart_LargeFileStreamsWithoutBuffering(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping large upload in short mode")
+	}
+
+	const size = 1 << 30 // 1 GiB
+
+	var received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mr, err := r.MultipartReader()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			n, _ := io.Copy(io.Discard, part)
+			received += n
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	files := []FilePart{{FieldName: "file", Filename: "big.bin", Reader: io.LimitReader(zeroReader{}, size)}}
+
+	// The streaming path pipes directly into the request body via io.Pipe, so this
+	// completing at all (rather than OOMing) is the behavior under test.
+	resp, err := Multipart(server.URL, nil, files, nil, 60*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if received != size {
+		t.Fatalf("expected server to receive %d bytes, got %d", size, received)
+	}
+}
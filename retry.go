@@ -0,0 +1,239 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/goantor/x"
+)
+
+// RetryPolicy 描述了请求失败后的重试策略
+type RetryPolicy struct {
+	MaxAttempts    int // 最大尝试次数, 含首次请求
+	BaseDelay      time.Duration
+	MaxDelay       time.Duration
+	Multiplier     float64
+	JitterFraction float64      // 抖动比例, 取值 [0, 1]
+	RetryOnStatus  []int        // 需要重试的响应状态码
+	RetryOnNetErr  bool         // 网络错误(超时/连接重置等)是否重试
+	RetryOnMethods []MethodType // 按状态码重试时允许的非幂等方法, GET 总是允许; 网络错误的重试不受方法限制
+}
+
+// DefaultRetryPolicy 返回一份开箱即用的重试策略
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		BaseDelay:      200 * time.Millisecond,
+		MaxDelay:       5 * time.Second,
+		Multiplier:     2,
+		JitterFraction: 1,
+		RetryOnStatus:  []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout},
+		RetryOnNetErr:  true,
+	}
+}
+
+func (p *RetryPolicy) isIdempotentMethod(method MethodType) bool {
+	if method == GetMethod {
+		return true
+	}
+
+	for _, m := range p.RetryOnMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *RetryPolicy) shouldRetryStatus(code int) bool {
+	for _, s := range p.RetryOnStatus {
+		if s == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delay 计算第 attempt 次重试前的等待时间: 退避值中 JitterFraction 比例的部分被随机化,
+// JitterFraction 为 1 时即 full-jitter 退避算法, 为 0 时不加抖动
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	backoff := float64(p.BaseDelay) * math.Pow(p.Multiplier, float64(attempt))
+	if max := float64(p.MaxDelay); backoff > max {
+		backoff = max
+	}
+
+	jitter := math.Min(math.Max(p.JitterFraction, 0), 1)
+	return time.Duration(backoff*(1-jitter) + rand.Float64()*backoff*jitter)
+}
+
+// retryAfterDelay 解析 Retry-After 响应头, 支持秒数与 HTTP-date 两种格式
+func retryAfterDelay(header http.Header) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+func isRetryableNetErr(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed)
+}
+
+// doWithRetry 与 do 相同, 但会依据 policy 在可重试的失败上退避重试; duration > 0 时作用于整个重试过程的
+// 截止时间, 而非单次尝试, mw 是本次调用专属的中间件, 与 doWithMiddlewares 的语义一致
+func doWithRetry(ctx context.Context, method MethodType, contentType ContentType, url string, params x.H, header http.Header, duration time.Duration, policy *RetryPolicy, mw []Middleware) (resp *http.Response, err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+
+	var cancel context.CancelFunc
+	if duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, duration)
+	}
+
+	body := getData(contentType, params)
+	var buf []byte
+	if body != nil {
+		if buf, err = io.ReadAll(body); err != nil {
+			if cancel != nil {
+				cancel()
+			}
+			return
+		}
+	}
+
+	client := clientPool
+	if len(mw) > 0 {
+		client = &http.Client{Transport: applyMiddlewares(clientPool.Transport, mw), Timeout: clientPool.Timeout}
+	}
+
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, string(method), url, bytes.NewReader(buf))
+		if err != nil {
+			if cancel != nil {
+				cancel()
+			}
+			return
+		}
+		req.Header = header
+
+		resp, err = client.Do(req)
+
+		retryable := false
+		if err != nil {
+			retryable = policy.RetryOnNetErr && isRetryableNetErr(err)
+		} else if policy.shouldRetryStatus(resp.StatusCode) && policy.isIdempotentMethod(method) {
+			retryable = true
+		}
+
+		if !retryable || attempt == attempts-1 {
+			if cancel != nil {
+				if err != nil {
+					cancel()
+				} else {
+					resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+				}
+			}
+			return
+		}
+
+		wait := policy.delay(attempt)
+		if resp != nil {
+			if d, ok := retryAfterDelay(resp.Header); ok {
+				wait = d
+			}
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+			if cancel != nil {
+				cancel()
+			}
+			return
+		}
+	}
+
+	if cancel != nil {
+		cancel()
+	}
+	return
+}
+
+// PostJsonWithRetry 与 PostJson 相同, 但失败时会按 policy 退避重试
+func PostJsonWithRetry(url string, params x.H, header http.Header, duration time.Duration, policy *RetryPolicy) (ret *Response, err error) {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", "application/json;charset=utf-8")
+
+	var resp *http.Response
+	if resp, err = doWithRetry(context.Background(), PostMethod, JsonType, url, params, header, duration, policy, nil); err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	return takeResponse(resp)
+}
+
+// FormWithRetry 与 PostForm 相同, 但失败时会按 policy 退避重试
+func FormWithRetry(url string, params x.H, header http.Header, duration time.Duration, policy *RetryPolicy) (ret *Response, err error) {
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Content-Type", string(FormType))
+
+	var resp *http.Response
+	if resp, err = doWithRetry(context.Background(), PostMethod, FormType, url, params, header, duration, policy, nil); err != nil {
+		return
+	}
+
+	defer resp.Body.Close()
+	return takeResponse(resp)
+}
@@ -0,0 +1,124 @@
+package requests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goantor/x"
+)
+
+func TestDoRequestRetry_RetriesUntilSuccess(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := &Request{
+		Method: GetMethod,
+		Url:    server.URL,
+		Retry: &RetryPolicy{
+			MaxAttempts:   5,
+			BaseDelay:     time.Millisecond,
+			MaxDelay:      10 * time.Millisecond,
+			Multiplier:    2,
+			RetryOnStatus: []int{http.StatusServiceUnavailable},
+			RetryOnNetErr: true,
+		},
+	}
+
+	resp, err := DoRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Fatalf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestDoRequestRetry_BackoffWithinBounds(t *testing.T) {
+	var mu sync.Mutex
+	var timestamps []time.Time
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		timestamps = append(timestamps, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := &RetryPolicy{
+		MaxAttempts:   3,
+		BaseDelay:     20 * time.Millisecond,
+		MaxDelay:      100 * time.Millisecond,
+		Multiplier:    2,
+		RetryOnStatus: []int{http.StatusServiceUnavailable},
+	}
+
+	resp, err := DoRequest(&Request{Method: GetMethod, Url: server.URL, Retry: policy})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(timestamps) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(timestamps))
+	}
+
+	for i := 1; i < len(timestamps); i++ {
+		gap := timestamps[i].Sub(timestamps[i-1])
+		if gap > policy.MaxDelay+50*time.Millisecond {
+			t.Fatalf("gap between attempt %d and %d exceeded backoff bound: %s", i, i+1, gap)
+		}
+	}
+}
+
+func TestDoRequestRetry_NonIdempotentNotRetriedByDefault(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	req := &Request{
+		Method:      PostMethod,
+		ContentType: JsonType,
+		Url:         server.URL,
+		Params:      x.H{"a": "b"},
+		Retry: &RetryPolicy{
+			MaxAttempts:   5,
+			BaseDelay:     time.Millisecond,
+			MaxDelay:      10 * time.Millisecond,
+			Multiplier:    2,
+			RetryOnStatus: []int{http.StatusServiceUnavailable},
+		},
+	}
+
+	resp, err := DoRequest(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected non-idempotent POST to not be retried by default, got %d attempts", got)
+	}
+}
@@ -0,0 +1,264 @@
+// Package stress 提供针对单个 requests.Request 的并发压测/负载测试能力
+package stress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goantor/requests"
+)
+
+// StressConfig 描述一次压测运行的参数
+type StressConfig struct {
+	Concurrency   int                            // 并发协程数
+	TotalRequests int                            // 固定请求总数, 与 Duration 二选一
+	Duration      time.Duration                  // 按时长压测, 与 TotalRequests 二选一
+	RatePerSecond int                            // 令牌桶限速, 0 表示不限速
+	Verify        func(*requests.Response) error // 校验单次响应是否符合预期, nil 表示请求出错或状态码 >= 500 即为失败
+}
+
+// Report 汇总一次压测的统计结果
+type Report struct {
+	Total       int64
+	Success     int64
+	Failure     int64
+	QPS         float64
+	AvgLatency  time.Duration
+	MinLatency  time.Duration
+	MaxLatency  time.Duration
+	P50Latency  time.Duration
+	P95Latency  time.Duration
+	P99Latency  time.Duration
+	StatusCodes map[int]int64
+}
+
+// StatusCode 返回一个只校验响应状态码的 Verify 函数
+func StatusCode(code int) func(*requests.Response) error {
+	return func(resp *requests.Response) error {
+		if resp.Code != code {
+			return fmt.Errorf("stress: expected status %d, got %d", code, resp.Code)
+		}
+		return nil
+	}
+}
+
+// JSONFieldEquals 返回一个校验响应体 JSON 中某字段是否等于 expected 的 Verify 函数
+func JSONFieldEquals(field string, expected interface{}) func(*requests.Response) error {
+	return func(resp *requests.Response) error {
+		var data map[string]interface{}
+		if err := json.Unmarshal(resp.Body, &data); err != nil {
+			return fmt.Errorf("stress: decode json: %w", err)
+		}
+
+		actual, ok := data[field]
+		if !ok {
+			return fmt.Errorf("stress: field %q not present in response", field)
+		}
+
+		if fmt.Sprintf("%v", actual) != fmt.Sprintf("%v", expected) {
+			return fmt.Errorf("stress: field %q: expected %v, got %v", field, expected, actual)
+		}
+
+		return nil
+	}
+}
+
+// Run 按 cfg 对 req 发起并发压测, 复用 requests 包共享的 clientPool
+func Run(req *requests.Request, cfg StressConfig) *Report {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+
+	var (
+		total, success, failure int64
+		statusMu                sync.Mutex
+		statusCodes             = map[int]int64{}
+		latencyMu               sync.Mutex
+		latencies               []time.Duration
+	)
+
+	var limiter <-chan time.Time
+	if cfg.RatePerSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(cfg.RatePerSecond))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	work := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(work)
+		if cfg.Duration > 0 {
+			deadline := time.After(cfg.Duration)
+			for {
+				select {
+				case <-deadline:
+					return
+				case <-done:
+					return
+				default:
+				}
+				if limiter != nil {
+					<-limiter
+				}
+				select {
+				case work <- struct{}{}:
+				case <-deadline:
+					return
+				}
+			}
+		}
+
+		n := cfg.TotalRequests
+		for i := 0; i < n; i++ {
+			if limiter != nil {
+				<-limiter
+			}
+			select {
+			case work <- struct{}{}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	progressDone := make(chan struct{})
+	go reportProgress(&total, &success, &failure, start, progressDone)
+
+	var wg sync.WaitGroup
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range work {
+				reqStart := time.Now()
+				ok := execute(req, cfg.Verify)
+				elapsed := time.Since(reqStart)
+
+				atomic.AddInt64(&total, 1)
+				if ok.err == nil {
+					atomic.AddInt64(&success, 1)
+				} else {
+					atomic.AddInt64(&failure, 1)
+				}
+
+				statusMu.Lock()
+				statusCodes[ok.status]++
+				statusMu.Unlock()
+
+				latencyMu.Lock()
+				latencies = append(latencies, elapsed)
+				latencyMu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(done)
+	close(progressDone)
+
+	report := buildReport(total, success, failure, time.Since(start), statusCodes, latencies)
+	fmt.Printf("stress: done total=%d success=%d failure=%d qps=%.2f p50=%s p95=%s p99=%s\n",
+		report.Total, report.Success, report.Failure, report.QPS, report.P50Latency, report.P95Latency, report.P99Latency)
+
+	return report
+}
+
+type execResult struct {
+	status int
+	err    error
+}
+
+func execute(req *requests.Request, verify func(*requests.Response) error) execResult {
+	resp, err := requests.DoRequest(req)
+	if err != nil {
+		return execResult{status: 0, err: err}
+	}
+	defer resp.Body.Close()
+
+	bs, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return execResult{status: resp.StatusCode, err: err}
+	}
+
+	result := &requests.Response{Code: resp.StatusCode, Body: bs}
+	if verify != nil {
+		if err = verify(result); err != nil {
+			return execResult{status: resp.StatusCode, err: err}
+		}
+	} else if resp.StatusCode >= http.StatusInternalServerError {
+		return execResult{status: resp.StatusCode, err: fmt.Errorf("stress: server error %d", resp.StatusCode)}
+	}
+
+	return execResult{status: resp.StatusCode, err: nil}
+}
+
+func reportProgress(total, success, failure *int64, start time.Time, done <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			t := atomic.LoadInt64(total)
+			qps := float64(t) / time.Since(start).Seconds()
+			fmt.Printf("stress: total=%d success=%d failure=%d qps=%.2f\n", t, atomic.LoadInt64(success), atomic.LoadInt64(failure), qps)
+		}
+	}
+}
+
+func buildReport(total, success, failure int64, elapsed time.Duration, statusCodes map[int]int64, latencies []time.Duration) *Report {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	report := &Report{
+		Total:       total,
+		Success:     success,
+		Failure:     failure,
+		StatusCodes: statusCodes,
+	}
+
+	if elapsed > 0 {
+		report.QPS = float64(total) / elapsed.Seconds()
+	}
+
+	if len(latencies) == 0 {
+		return report
+	}
+
+	var sum time.Duration
+	for _, l := range latencies {
+		sum += l
+	}
+
+	report.AvgLatency = sum / time.Duration(len(latencies))
+	report.MinLatency = latencies[0]
+	report.MaxLatency = latencies[len(latencies)-1]
+	report.P50Latency = percentile(latencies, 50)
+	report.P95Latency = percentile(latencies, 95)
+	report.P99Latency = percentile(latencies, 99)
+
+	return report
+}
+
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	idx := (len(sorted) * p) / 100
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+
+	return sorted[idx]
+}
@@ -0,0 +1,50 @@
+package stress
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/goantor/requests"
+)
+
+func TestRun_FixedCount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := &requests.Request{Method: requests.GetMethod, Url: server.URL}
+	report := Run(req, StressConfig{Concurrency: 4, TotalRequests: 20, Verify: StatusCode(http.StatusOK)})
+
+	if report.Total != 20 {
+		t.Fatalf("expected 20 total requests, got %d", report.Total)
+	}
+	if report.Success != 20 {
+		t.Fatalf("expected 20 successes, got %d", report.Success)
+	}
+	if report.Failure != 0 {
+		t.Fatalf("expected 0 failures, got %d", report.Failure)
+	}
+}
+
+func TestRun_DurationBounded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	req := &requests.Request{Method: requests.GetMethod, Url: server.URL}
+
+	start := time.Now()
+	report := Run(req, StressConfig{Concurrency: 2, Duration: 300 * time.Millisecond})
+	elapsed := time.Since(start)
+
+	if report.Total == 0 {
+		t.Fatal("expected at least one request to complete during the run")
+	}
+	if elapsed > 2*time.Second {
+		t.Fatalf("duration-bounded run took too long: %s", elapsed)
+	}
+}